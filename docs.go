@@ -0,0 +1,249 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// runDocs implements `tfmodulegen docs <module-dir>`: it parses the
+// variable.tf, output.tf and versions.tf files of an existing module and
+// regenerates README.md, preserving any hand-written content between
+// BEGIN_CUSTOM/END_CUSTOM markers.
+func runDocs(args []string) error {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	templatesDir := fs.String("templates-dir", "", "directory of template overrides (falls back to built-in defaults)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		return fmt.Errorf("usage: tfmodulegen docs <module-dir>")
+	}
+	dir := fs.Arg(0)
+
+	moduleName := filepath.Base(strings.TrimRight(dir, string(filepath.Separator)))
+
+	providers, tfVersion, err := parseVersionsFile(filepath.Join(dir, "versions.tf"))
+	if err != nil {
+		return fmt.Errorf("parsing versions.tf: %w", err)
+	}
+
+	variables, err := parseVariablesFile(filepath.Join(dir, "variable.tf"))
+	if err != nil {
+		return fmt.Errorf("parsing variable.tf: %w", err)
+	}
+
+	outputs, err := parseOutputsFile(filepath.Join(dir, "output.tf"))
+	if err != nil {
+		return fmt.Errorf("parsing output.tf: %w", err)
+	}
+
+	readmePath := filepath.Join(dir, "README.md")
+	custom, description := extractCustomSection(readmePath, moduleName)
+
+	readme, err := renderReadme(ReadmeData{
+		ModuleName:       moduleName,
+		Description:      description,
+		TerraformVersion: tfVersion,
+		Providers:        providers,
+		Variables:        variables,
+		Outputs:          outputs,
+		Custom:           custom,
+	}, defaultResolver(*templatesDir))
+	if err != nil {
+		return fmt.Errorf("rendering readme: %w", err)
+	}
+
+	if err := os.WriteFile(readmePath, []byte(readme), 0644); err != nil {
+		return fmt.Errorf("writing README.md: %w", err)
+	}
+
+	fmt.Printf("Regenerated %s\n", readmePath)
+	return nil
+}
+
+// extractCustomSection reads an existing README (if any) and returns the
+// hand-written content between BEGIN_CUSTOM/END_CUSTOM markers, along with
+// the existing module description (the first non-empty line after the
+// title), so regeneration doesn't wipe out prose the user already wrote.
+func extractCustomSection(readmePath, moduleName string) (custom, description string) {
+	content, err := os.ReadFile(readmePath)
+	if err != nil {
+		return "", ""
+	}
+
+	text := string(content)
+	if begin := strings.Index(text, beginCustomMarker); begin != -1 {
+		if end := strings.Index(text, endCustomMarker); end != -1 && end > begin {
+			inner := text[begin+len(beginCustomMarker) : end]
+			custom = beginCustomMarker + inner + endCustomMarker
+		}
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == fmt.Sprintf("# %s", moduleName) {
+			for _, rest := range lines[i+1:] {
+				rest = strings.TrimSpace(rest)
+				if rest == "" {
+					continue
+				}
+				description = rest
+				break
+			}
+			break
+		}
+	}
+
+	return custom, description
+}
+
+// parseVersionsFile extracts the required Terraform version and provider
+// requirements from a versions.tf file.
+func parseVersionsFile(path string) ([]Provider, string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+
+	f, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, "", diags
+	}
+
+	var tfVersion string
+	var providers []Provider
+
+	for _, block := range f.Body().Blocks() {
+		if block.Type() != "terraform" {
+			continue
+		}
+		if attr := block.Body().GetAttribute("required_version"); attr != nil {
+			tfVersion = unquote(attrRawValue(attr))
+		}
+		for _, inner := range block.Body().Blocks() {
+			if inner.Type() != "required_providers" {
+				continue
+			}
+			for name, attr := range inner.Body().Attributes() {
+				providers = append(providers, Provider{
+					Name:    name,
+					Source:  unquote(objectField(attr, "source")),
+					Version: unquote(objectField(attr, "version")),
+				})
+			}
+		}
+	}
+
+	return providers, tfVersion, nil
+}
+
+// parseVariablesFile extracts one VariableDoc per `variable` block.
+func parseVariablesFile(path string) ([]VariableDoc, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	f, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var variables []VariableDoc
+	for _, block := range f.Body().Blocks() {
+		if block.Type() != "variable" || len(block.Labels()) == 0 {
+			continue
+		}
+
+		doc := VariableDoc{Name: block.Labels()[0]}
+		if attr := block.Body().GetAttribute("description"); attr != nil {
+			doc.Description = unquote(attrRawValue(attr))
+		}
+		if attr := block.Body().GetAttribute("type"); attr != nil {
+			doc.Type = attrRawValue(attr)
+		}
+		if attr := block.Body().GetAttribute("default"); attr != nil {
+			doc.Default = attrRawValue(attr)
+		} else {
+			doc.Required = true
+		}
+		variables = append(variables, doc)
+	}
+
+	return variables, nil
+}
+
+// parseOutputsFile extracts one OutputDoc per `output` block.
+func parseOutputsFile(path string) ([]OutputDoc, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	f, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var outputs []OutputDoc
+	for _, block := range f.Body().Blocks() {
+		if block.Type() != "output" || len(block.Labels()) == 0 {
+			continue
+		}
+
+		doc := OutputDoc{Name: block.Labels()[0]}
+		if attr := block.Body().GetAttribute("description"); attr != nil {
+			doc.Description = unquote(attrRawValue(attr))
+		}
+		outputs = append(outputs, doc)
+	}
+
+	return outputs, nil
+}
+
+// attrRawValue renders an hclwrite attribute's expression back to source
+// text, trimmed of surrounding whitespace.
+func attrRawValue(attr *hclwrite.Attribute) string {
+	return strings.TrimSpace(string(attr.Expr().BuildTokens(nil).Bytes()))
+}
+
+// objectField pulls a single field (e.g. "source" or "version") out of an
+// attribute whose expression is an object constructor, as used in the
+// modern `required_providers` block form.
+func objectField(attr *hclwrite.Attribute, field string) string {
+	raw := attrRawValue(attr)
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, field) {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+	return ""
+}
+
+// unquote strips surrounding double quotes from a raw HCL literal, leaving
+// anything else (references, expressions) untouched.
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	return path
+}
+
+func TestParseVariablesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "variable.tf", `
+variable "region" {
+  description = "AWS region"
+  type        = string
+  default     = "us-east-1"
+}
+
+variable "name" {
+  description = "Resource name"
+  type        = string
+}
+`)
+
+	got, err := parseVariablesFile(path)
+	if err != nil {
+		t.Fatalf("parseVariablesFile: %v", err)
+	}
+
+	want := []VariableDoc{
+		{Name: "region", Description: "AWS region", Type: "string", Default: `"us-east-1"`, Required: false},
+		{Name: "name", Description: "Resource name", Type: "string", Default: "", Required: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseVariablesFile() = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseVariablesFileMissing(t *testing.T) {
+	got, err := parseVariablesFile(filepath.Join(t.TempDir(), "variable.tf"))
+	if err != nil {
+		t.Fatalf("parseVariablesFile on missing file: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("parseVariablesFile on missing file = %#v, want nil", got)
+	}
+}
+
+func TestParseOutputsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "output.tf", `
+output "arn" {
+  description = "Resource ARN"
+  value       = aws_instance.this.arn
+}
+`)
+
+	got, err := parseOutputsFile(path)
+	if err != nil {
+		t.Fatalf("parseOutputsFile: %v", err)
+	}
+
+	want := []OutputDoc{
+		{Name: "arn", Description: "Resource ARN"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseOutputsFile() = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseVersionsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "versions.tf", `
+terraform {
+  required_version = ">= 1.0"
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "5.0.0"
+    }
+  }
+}
+`)
+
+	providers, tfVersion, err := parseVersionsFile(path)
+	if err != nil {
+		t.Fatalf("parseVersionsFile: %v", err)
+	}
+
+	if tfVersion != ">= 1.0" {
+		t.Errorf("tfVersion = %q, want %q", tfVersion, ">= 1.0")
+	}
+
+	want := []Provider{{Name: "aws", Source: "hashicorp/aws", Version: "5.0.0"}}
+	if !reflect.DeepEqual(providers, want) {
+		t.Fatalf("providers = %#v, want %#v", providers, want)
+	}
+}
+
+func TestParseVersionsFileMissing(t *testing.T) {
+	providers, tfVersion, err := parseVersionsFile(filepath.Join(t.TempDir(), "versions.tf"))
+	if err != nil {
+		t.Fatalf("parseVersionsFile on missing file: %v", err)
+	}
+	if providers != nil || tfVersion != "" {
+		t.Fatalf("parseVersionsFile on missing file = (%#v, %q), want (nil, \"\")", providers, tfVersion)
+	}
+}
+
+func TestExtractCustomSection(t *testing.T) {
+	dir := t.TempDir()
+	readmePath := writeTestFile(t, dir, "README.md", `# mymodule
+A hand-written description.
+
+<!-- BEGIN_CUSTOM -->
+Some notes a human wrote.
+<!-- END_CUSTOM -->
+
+## Usage
+`)
+
+	custom, description := extractCustomSection(readmePath, "mymodule")
+
+	if description != "A hand-written description." {
+		t.Errorf("description = %q, want %q", description, "A hand-written description.")
+	}
+
+	wantCustom := "<!-- BEGIN_CUSTOM -->\nSome notes a human wrote.\n<!-- END_CUSTOM -->"
+	if custom != wantCustom {
+		t.Errorf("custom = %q, want %q", custom, wantCustom)
+	}
+}
+
+func TestExtractCustomSectionNoReadme(t *testing.T) {
+	custom, description := extractCustomSection(filepath.Join(t.TempDir(), "README.md"), "mymodule")
+	if custom != "" || description != "" {
+		t.Fatalf("extractCustomSection on missing README = (%q, %q), want (\"\", \"\")", custom, description)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// defaultExamples is used when neither -example nor the config's examples
+// list name any examples to scaffold.
+var defaultExamples = []string{"basic"}
+
+// ExampleData is the data used to render the files of a single example
+// under a module's examples/ directory.
+type ExampleData struct {
+	ModuleName  string
+	ExampleName string
+	Description string
+}
+
+// generateExamples scaffolds an examples/<name>/ subdirectory for each name
+// in exampleNames under moduleDir.
+func generateExamples(moduleDir string, data ModuleData, exampleNames []string, resolver TemplateResolver) error {
+	files := []struct {
+		filename     string
+		templateName string
+	}{
+		{"main.tf", templateExampleMain},
+		{"variables.tf", templateExampleVariables},
+		{"outputs.tf", templateExampleOutputs},
+		{"terraform.tfvars.example", templateExampleTfvars},
+		{"README.md", templateExampleReadme},
+	}
+
+	for _, name := range exampleNames {
+		exampleDir := filepath.Join(moduleDir, "examples", name)
+		if err := os.MkdirAll(exampleDir, 0755); err != nil {
+			return fmt.Errorf("creating examples/%s: %w", name, err)
+		}
+
+		exampleData := ExampleData{
+			ModuleName:  data.ModuleName,
+			ExampleName: name,
+			Description: data.Description,
+		}
+
+		for _, f := range files {
+			if err := renderExampleFile(exampleDir, f.filename, f.templateName, exampleData, resolver); err != nil {
+				return fmt.Errorf("generating examples/%s/%s: %w", name, f.filename, err)
+			}
+		}
+	}
+	return nil
+}
+
+// renderExampleFile resolves templateName through resolver and executes it
+// with data, writing the result to filename inside dir.
+func renderExampleFile(dir, filename, templateName string, data ExampleData, resolver TemplateResolver) error {
+	tmplStr, err := resolver.Get(templateName)
+	if err != nil {
+		return fmt.Errorf("resolving template: %w", err)
+	}
+
+	tmpl, err := template.New(templateName).Funcs(template.FuncMap{
+		"codeFence": func() string { return "```" },
+	}).Parse(tmplStr)
+	if err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(dir, filename), buf.Bytes(), 0644)
+}
+
+// splitCSV splits a comma-separated flag value into trimmed, non-empty
+// parts.
+func splitCSV(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -3,9 +3,12 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
 )
@@ -17,12 +20,83 @@ type Provider struct {
 	Version string `json:"version"`
 }
 
+// VariableSpec describes a variable to render into variable.tf. Default is
+// spliced into the template as a raw HCL expression (e.g. "10", "true",
+// `["a", "b"]`, or an already-quoted string), except for type = "string",
+// where a plain value that isn't already quoted is quoted automatically by
+// normalizeVariableDefault so a manifest can write default: "10.0.0.0/16"
+// instead of the awkward default: "\"10.0.0.0/16\"".
+type VariableSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Default     string `json:"default,omitempty"`
+}
+
+// OutputSpec describes an output to render into output.tf. Value is
+// spliced into the template as a raw HCL expression (e.g.
+// aws_instance.this.arn, or an already-quoted string literal); it isn't
+// normalized like VariableSpec.Default since output values are references
+// far more often than plain literals.
+type OutputSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Value       string `json:"value"`
+}
+
+// normalizeVariableDefault ensures a manifest-supplied Default for a
+// type = "string" variable is valid HCL: a plain value that isn't already
+// quoted is wrapped in quotes. Values for other types, and values that are
+// already quoted, are passed through unchanged so callers can still supply
+// an arbitrary HCL expression (a list, a map, a reference, ...).
+func normalizeVariableDefault(varType, raw string) string {
+	if varType != "string" || raw == "" {
+		return raw
+	}
+	if strings.HasPrefix(raw, `"`) && strings.HasSuffix(raw, `"`) && len(raw) >= 2 {
+		return raw
+	}
+	return strconv.Quote(raw)
+}
+
 // ModuleData is the data used to generate the module files.
 type ModuleData struct {
 	ModuleName       string
 	Description      string
 	TerraformVersion string
 	Providers        []Provider
+	Variables        []VariableSpec
+	Outputs          []OutputSpec
+	Examples         []string
+}
+
+// variableDocs converts manifest/flag-supplied variable specs into the
+// VariableDoc shape renderReadme expects for the Inputs table.
+func variableDocs(specs []VariableSpec) []VariableDoc {
+	var docs []VariableDoc
+	for _, s := range specs {
+		docs = append(docs, VariableDoc{
+			Name:        s.Name,
+			Description: s.Description,
+			Type:        s.Type,
+			Default:     s.Default,
+			Required:    s.Default == "",
+		})
+	}
+	return docs
+}
+
+// outputDocs converts manifest/flag-supplied output specs into the
+// OutputDoc shape renderReadme expects for the Outputs table.
+func outputDocs(specs []OutputSpec) []OutputDoc {
+	var docs []OutputDoc
+	for _, s := range specs {
+		docs = append(docs, OutputDoc{
+			Name:        s.Name,
+			Description: s.Description,
+		})
+	}
+	return docs
 }
 
 // Config is the configuration for the module generator.
@@ -30,36 +104,250 @@ type ModuleData struct {
 type Config struct {
 	TerraformVersion string     `json:"terraform_version"`
 	Providers        []Provider `json:"providers"`
+	TemplatesDir     string     `json:"templates_dir"`
+	Examples         []string   `json:"examples"`
+}
+
+// Manifest is the JSON shape accepted by -manifest and -stdin: a full
+// ModuleData specification for non-interactive generation.
+type Manifest struct {
+	ModuleName       string         `json:"module_name"`
+	Description      string         `json:"description"`
+	TerraformVersion string         `json:"terraform_version"`
+	Providers        []Provider     `json:"providers"`
+	Variables        []VariableSpec `json:"variables,omitempty"`
+	Outputs          []OutputSpec   `json:"outputs,omitempty"`
+	Examples         []string       `json:"examples,omitempty"`
 }
 
 func main() {
-	reader := bufio.NewReader(os.Stdin)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "docs":
+			if err := runDocs(os.Args[2:]); err != nil {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+			return
+		case "upgrade":
+			if err := runUpgrade(os.Args[2:]); err != nil {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
 
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+}
+
+// flagSet groups the flags accepted by the root command.
+type flagSet struct {
+	name           string
+	description    string
+	tfVersion      string
+	providers      providerFlags
+	outDir         string
+	manifestPath   string
+	useStdin       bool
+	nonInteractive bool
+	templatesDir   string
+	offline        bool
+	registryURL    string
+	examples       string
+}
+
+// providerFlags accumulates repeated -provider name=source@version flags.
+type providerFlags []string
+
+func (p *providerFlags) String() string { return strings.Join(*p, ",") }
+func (p *providerFlags) Set(v string) error {
+	*p = append(*p, v)
+	return nil
+}
+
+// run parses the root command's flags and dispatches to the manifest,
+// non-interactive, or interactive generation path, converging on Generate.
+func run(args []string) error {
+	fs := flag.NewFlagSet("tfmodulegen", flag.ExitOnError)
+	flags := flagSet{}
+	fs.StringVar(&flags.name, "name", "", "module name")
+	fs.StringVar(&flags.description, "description", "", "module description")
+	fs.StringVar(&flags.tfVersion, "tf-version", "", "required Terraform version")
+	fs.Var(&flags.providers, "provider", "provider in the form name=source@version (repeatable)")
+	fs.StringVar(&flags.outDir, "out-dir", "", "directory to generate the module into (default: module name)")
+	fs.StringVar(&flags.manifestPath, "manifest", "", "path to a JSON file describing the module to generate")
+	fs.BoolVar(&flags.useStdin, "stdin", false, "read the module JSON manifest from stdin")
+	fs.BoolVar(&flags.nonInteractive, "non-interactive", false, "fail instead of prompting when required fields are missing")
+	fs.StringVar(&flags.templatesDir, "templates-dir", "", "directory of template overrides (falls back to built-in defaults)")
+	fs.BoolVar(&flags.offline, "offline", false, "disable Terraform Registry provider lookups")
+	fs.StringVar(&flags.registryURL, "registry-url", defaultRegistryURL, "Terraform Registry base URL used for provider lookups")
+	fs.StringVar(&flags.examples, "example", "", "comma-separated list of examples to scaffold under examples/ (default: basic)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	config := loadConfig("tfmodulegen.config.json")
+	if flags.templatesDir == "" {
+		flags.templatesDir = config.TemplatesDir
+	}
+
+	var (
+		data ModuleData
+		err  error
+	)
+	switch {
+	case flags.useStdin:
+		data, err = moduleDataFromManifest(os.Stdin)
+	case flags.manifestPath != "":
+		file, openErr := os.Open(flags.manifestPath)
+		if openErr != nil {
+			return fmt.Errorf("opening manifest: %w", openErr)
+		}
+		defer file.Close()
+		data, err = moduleDataFromManifest(file)
+	case flags.nonInteractive:
+		data, err = buildFromFlags(flags, config)
+	default:
+		reader := bufio.NewReader(os.Stdin)
+		var registry Registry
+		if !flags.offline {
+			registry = newHTTPRegistry(flags.registryURL, reader)
+		}
+		data, err = promptInteractive(reader, config, registry)
+	}
+	if err != nil {
+		return err
+	}
+
+	outDir := flags.outDir
+	if outDir == "" {
+		outDir = data.ModuleName
+	}
+
+	if len(data.Examples) == 0 {
+		switch {
+		case flags.examples != "":
+			data.Examples = splitCSV(flags.examples)
+		case len(config.Examples) > 0:
+			data.Examples = config.Examples
+		default:
+			data.Examples = defaultExamples
+		}
+	}
+
+	return Generate(outDir, data, defaultResolver(flags.templatesDir))
+}
+
+// loadConfig reads tfmodulegen.config.json if present, returning a zero
+// Config on any error (matching the original best-effort behavior).
+func loadConfig(path string) Config {
 	var config Config
-	configPath := "tfmodulegen.config.json"
-	if _, err := os.Stat(configPath); err == nil {
-		file, err := os.Open(configPath)
-		if err != nil {
-			fmt.Println("Error opening config file:", err)
-		} else {
-			defer file.Close()
-			decoder := json.NewDecoder(file)
-			if err := decoder.Decode(&config); err != nil {
-				fmt.Println("Error decoding config file:", err)
-			} else {
-				fmt.Println("Loaded configuration from", configPath)
+	file, err := os.Open(path)
+	if err != nil {
+		return config
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&config); err != nil {
+		fmt.Println("Error decoding config file:", err)
+		return Config{}
+	}
+	fmt.Println("Loaded configuration from", path)
+	return config
+}
+
+// moduleDataFromManifest decodes a Manifest from r and converts it to
+// ModuleData.
+func moduleDataFromManifest(r io.Reader) (ModuleData, error) {
+	var m Manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return ModuleData{}, fmt.Errorf("decoding manifest: %w", err)
+	}
+	if m.ModuleName == "" {
+		return ModuleData{}, fmt.Errorf("manifest is missing module_name")
+	}
+	for i := range m.Variables {
+		m.Variables[i].Default = normalizeVariableDefault(m.Variables[i].Type, m.Variables[i].Default)
+	}
+
+	return ModuleData{
+		ModuleName:       m.ModuleName,
+		Description:      m.Description,
+		TerraformVersion: m.TerraformVersion,
+		Providers:        m.Providers,
+		Variables:        m.Variables,
+		Outputs:          m.Outputs,
+		Examples:         m.Examples,
+	}, nil
+}
+
+// buildFromFlags assembles ModuleData from command-line flags, falling back
+// to config for Terraform version and providers when not given on the
+// command line. It fails with a clear error if required fields are missing.
+func buildFromFlags(flags flagSet, config Config) (ModuleData, error) {
+	if flags.name == "" {
+		return ModuleData{}, fmt.Errorf("-name is required in non-interactive mode")
+	}
+
+	tfVersion := flags.tfVersion
+	if tfVersion == "" {
+		tfVersion = config.TerraformVersion
+	}
+	if tfVersion == "" {
+		tfVersion = ">= 0.12"
+	}
+
+	providers := config.Providers
+	if len(flags.providers) > 0 {
+		providers = nil
+		for _, raw := range flags.providers {
+			p, err := parseProviderFlag(raw)
+			if err != nil {
+				return ModuleData{}, err
 			}
+			providers = append(providers, p)
 		}
 	}
 
-	// Start the interactive module generation process.
+	return ModuleData{
+		ModuleName:       flags.name,
+		Description:      flags.description,
+		TerraformVersion: tfVersion,
+		Providers:        providers,
+	}, nil
+}
 
+// parseProviderFlag parses a -provider flag of the form
+// "name=source@version" into a Provider.
+func parseProviderFlag(raw string) (Provider, error) {
+	nameRest := strings.SplitN(raw, "=", 2)
+	if len(nameRest) != 2 || nameRest[0] == "" {
+		return Provider{}, fmt.Errorf("invalid -provider %q: expected name=source@version", raw)
+	}
+
+	sourceVersion := strings.SplitN(nameRest[1], "@", 2)
+	provider := Provider{Name: nameRest[0], Source: sourceVersion[0]}
+	if len(sourceVersion) == 2 {
+		provider.Version = sourceVersion[1]
+	}
+	return provider, nil
+}
+
+// promptInteractive drives the original prompt-based module generation
+// flow, reading answers from reader. If registry is non-nil, it's used to
+// auto-suggest a provider's source and latest version when the user leaves
+// those blank.
+func promptInteractive(reader *bufio.Reader, config Config, registry Registry) (ModuleData, error) {
 	// Input module name.
 	fmt.Print("Enter module name: ")
 	moduleName, err := reader.ReadString('\n')
 	if err != nil {
-		fmt.Println("Error reading module name:", err)
-		return
+		return ModuleData{}, fmt.Errorf("reading module name: %w", err)
 	}
 	moduleName = strings.TrimSpace(moduleName)
 
@@ -67,8 +355,7 @@ func main() {
 	fmt.Print("Enter module description: ")
 	description, err := reader.ReadString('\n')
 	if err != nil {
-		fmt.Println("Error reading description:", err)
-		return
+		return ModuleData{}, fmt.Errorf("reading description: %w", err)
 	}
 	description = strings.TrimSpace(description)
 
@@ -83,8 +370,7 @@ func main() {
 	}
 	tfVersion, err := reader.ReadString('\n')
 	if err != nil {
-		fmt.Println("Error reading Terraform version:", err)
-		return
+		return ModuleData{}, fmt.Errorf("reading Terraform version: %w", err)
 	}
 	tfVersion = strings.TrimSpace(tfVersion)
 	if tfVersion == "" {
@@ -107,8 +393,7 @@ func main() {
 			fmt.Print("Do you want to add a provider? (y/n): ")
 			ans, err := reader.ReadString('\n')
 			if err != nil {
-				fmt.Println("Error reading input:", err)
-				return
+				return ModuleData{}, fmt.Errorf("reading input: %w", err)
 			}
 			ans = strings.ToLower(strings.TrimSpace(ans))
 			if ans != "y" && ans != "yes" {
@@ -118,26 +403,46 @@ func main() {
 			fmt.Print("Enter provider name (e.g. google): ")
 			providerName, err := reader.ReadString('\n')
 			if err != nil {
-				fmt.Println("Error reading provider name:", err)
-				return
+				return ModuleData{}, fmt.Errorf("reading provider name: %w", err)
 			}
 			providerName = strings.TrimSpace(providerName)
 
-			fmt.Print("Enter provider source (e.g. hashicorp/google): ")
+			var suggested Provider
+			if registry != nil {
+				if looked, err := registry.Lookup(providerName); err == nil {
+					suggested = looked
+				} else {
+					fmt.Println("Registry lookup failed, falling back to manual entry:", err)
+				}
+			}
+
+			sourcePrompt := "Enter provider source (e.g. hashicorp/google): "
+			if suggested.Source != "" {
+				sourcePrompt = fmt.Sprintf("Enter provider source (default from registry: %s): ", suggested.Source)
+			}
+			fmt.Print(sourcePrompt)
 			providerSource, err := reader.ReadString('\n')
 			if err != nil {
-				fmt.Println("Error reading provider source:", err)
-				return
+				return ModuleData{}, fmt.Errorf("reading provider source: %w", err)
 			}
 			providerSource = strings.TrimSpace(providerSource)
+			if providerSource == "" {
+				providerSource = suggested.Source
+			}
 
-			fmt.Print("Enter provider version (e.g. 6.4.0): ")
+			versionPrompt := "Enter provider version (e.g. 6.4.0): "
+			if suggested.Version != "" {
+				versionPrompt = fmt.Sprintf("Enter provider version (default from registry: %s): ", suggested.Version)
+			}
+			fmt.Print(versionPrompt)
 			providerVersion, err := reader.ReadString('\n')
 			if err != nil {
-				fmt.Println("Error reading provider version:", err)
-				return
+				return ModuleData{}, fmt.Errorf("reading provider version: %w", err)
 			}
 			providerVersion = strings.TrimSpace(providerVersion)
+			if providerVersion == "" {
+				providerVersion = suggested.Version
+			}
 
 			providers = append(providers, Provider{
 				Name:    providerName,
@@ -147,48 +452,78 @@ func main() {
 		}
 	}
 
-	data := ModuleData{
+	return ModuleData{
 		ModuleName:       moduleName,
 		Description:      description,
 		TerraformVersion: tfVersion,
 		Providers:        providers,
-	}
+	}, nil
+}
 
-	// Create the module directory.
-	if err := os.Mkdir(moduleName, 0755); err != nil {
+// Generate writes the full set of module boilerplate files for data into
+// dir, creating the directory if necessary. This is the single entry point
+// shared by the interactive, flag-driven, and manifest-driven paths.
+func Generate(dir string, data ModuleData, resolver TemplateResolver) error {
+	if err := os.Mkdir(dir, 0755); err != nil {
 		if !os.IsExist(err) {
-			fmt.Println("Error creating directory:", err)
-			return
-		} else {
-			fmt.Printf("Directory '%s' already exists. Files will be overwritten if they exist.\n", moduleName)
+			return fmt.Errorf("creating directory: %w", err)
 		}
+		fmt.Printf("Directory '%s' already exists. Files will be overwritten if they exist.\n", dir)
 	}
 
 	files := []struct {
-		filename string
-		tmpl     string
+		filename     string
+		templateName string
 	}{
-		{"versions.tf", versionsTemplate},
-		{"main.tf", mainTemplate},
-		{"output.tf", outputTemplate},
-		{"variable.tf", variableTemplate},
-		{"README.md", readmeTemplate},
+		{"versions.tf", templateVersions},
+		{"main.tf", templateMain},
+		{"output.tf", templateOutput},
+		{"variable.tf", templateVariable},
 	}
 
 	for _, f := range files {
-		if err := generateFile(moduleName, f.filename, f.tmpl, data); err != nil {
-			fmt.Printf("Failed to generate %s: %v\n", f.filename, err)
-			return
+		if err := generateFile(dir, f.filename, f.templateName, data, resolver); err != nil {
+			return fmt.Errorf("generating %s: %w", f.filename, err)
 		}
 	}
 
-	fmt.Printf("Terraform module boilerplate files generated successfully in the '%s' directory!\n", moduleName)
+	readme, err := renderReadme(ReadmeData{
+		ModuleName:       data.ModuleName,
+		Description:      data.Description,
+		TerraformVersion: data.TerraformVersion,
+		Providers:        data.Providers,
+		Variables:        variableDocs(data.Variables),
+		Outputs:          outputDocs(data.Outputs),
+	}, resolver)
+	if err != nil {
+		return fmt.Errorf("rendering readme: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(readme), 0644); err != nil {
+		return fmt.Errorf("writing README.md: %w", err)
+	}
+
+	examples := data.Examples
+	if len(examples) == 0 {
+		examples = defaultExamples
+	}
+	if err := generateExamples(dir, data, examples, resolver); err != nil {
+		return fmt.Errorf("generating examples: %w", err)
+	}
+
+	fmt.Printf("Terraform module boilerplate files generated successfully in the '%s' directory!\n", dir)
+	return nil
 }
 
-// generateFile generates a file with the given template and data.
-func generateFile(dirName, filename, tmplStr string, data ModuleData) error {
+// generateFile generates a file by resolving templateName through resolver
+// and executing it with data.
+func generateFile(dirName, filename, templateName string, data ModuleData, resolver TemplateResolver) error {
+	tmplStr, err := resolver.Get(templateName)
+	if err != nil {
+		return fmt.Errorf("resolving template: %w", err)
+	}
+
 	filePath := filepath.Join(dirName, filename)
-	tmpl, err := template.New(filename).Funcs(template.FuncMap{
+	tmpl, err := template.New(templateName).Funcs(template.FuncMap{
 		"codeFence": func() string { return "```" },
 	}).Parse(tmplStr)
 	if err != nil {
@@ -206,67 +541,3 @@ func generateFile(dirName, filename, tmplStr string, data ModuleData) error {
 	}
 	return nil
 }
-
-const versionsTemplate = `terraform {
-  required_version = "{{.TerraformVersion}}"
-  {{- if .Providers }}
-  required_providers {
-  {{- range .Providers }}
-    {{ .Name }} = {
-      source  = "{{ .Source }}"
-      version = "{{ .Version }}"
-    }
-  {{- end }}
-  }
-  {{- end }}
-}
-`
-
-const mainTemplate = `// Main configuration for module {{.ModuleName}}
-resource "example_resource" "default" {
-  provisioner "local-exec" {
-    command = "echo Hello from module {{.ModuleName}}!"
-  }
-}
-`
-
-const outputTemplate = `// Outputs for module {{.ModuleName}}
-output "example" {
-  description = "An example output"
-  value       = "example_value"
-}
-`
-
-const variableTemplate = `// Variables for module {{.ModuleName}}
-variable "example_variable" {
-  description = "An example variable"
-  type        = string
-  default     = "default_value"
-}
-`
-
-const readmeTemplate = `# {{.ModuleName}}
-{{.Description}}
-
-This Terraform module is automatically generated.
-
-## Requirements
-
-- Terraform version {{.TerraformVersion}}
-
-{{- if .Providers }}
-## Providers
-{{- range .Providers }}
-- **{{ .Name }}**: source={{ .Source }}, version={{ .Version }}
-{{- end }}
-{{- end }}
-
-## Usage
-
-{{codeFence}}hcl
-module "{{.ModuleName}}" {
-  source = "./{{.ModuleName}}"
-  # ... module inputs
-}
-{{codeFence}}
-`
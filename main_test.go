@@ -0,0 +1,161 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProviderFlag(t *testing.T) {
+	p, err := parseProviderFlag("aws=hashicorp/aws@5.0.0")
+	if err != nil {
+		t.Fatalf("parseProviderFlag: %v", err)
+	}
+	want := Provider{Name: "aws", Source: "hashicorp/aws", Version: "5.0.0"}
+	if p != want {
+		t.Fatalf("parseProviderFlag() = %#v, want %#v", p, want)
+	}
+}
+
+func TestParseProviderFlagNoVersion(t *testing.T) {
+	p, err := parseProviderFlag("aws=hashicorp/aws")
+	if err != nil {
+		t.Fatalf("parseProviderFlag: %v", err)
+	}
+	want := Provider{Name: "aws", Source: "hashicorp/aws"}
+	if p != want {
+		t.Fatalf("parseProviderFlag() = %#v, want %#v", p, want)
+	}
+}
+
+func TestParseProviderFlagInvalid(t *testing.T) {
+	if _, err := parseProviderFlag("aws"); err == nil {
+		t.Fatal("parseProviderFlag(\"aws\") = nil error, want error")
+	}
+	if _, err := parseProviderFlag("=hashicorp/aws"); err == nil {
+		t.Fatal("parseProviderFlag(\"=hashicorp/aws\") = nil error, want error")
+	}
+}
+
+func TestBuildFromFlagsRequiresName(t *testing.T) {
+	_, err := buildFromFlags(flagSet{}, Config{})
+	if err == nil {
+		t.Fatal("buildFromFlags with no -name = nil error, want error")
+	}
+}
+
+func TestBuildFromFlagsDefaults(t *testing.T) {
+	got, err := buildFromFlags(flagSet{name: "vpc", description: "a vpc"}, Config{})
+	if err != nil {
+		t.Fatalf("buildFromFlags: %v", err)
+	}
+	want := ModuleData{ModuleName: "vpc", Description: "a vpc", TerraformVersion: ">= 0.12"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("buildFromFlags() = %#v, want %#v", got, want)
+	}
+}
+
+func TestBuildFromFlagsFlagOverridesConfig(t *testing.T) {
+	config := Config{
+		TerraformVersion: ">= 1.0",
+		Providers:        []Provider{{Name: "google", Source: "hashicorp/google", Version: "6.0.0"}},
+	}
+
+	got, err := buildFromFlags(flagSet{name: "vpc"}, config)
+	if err != nil {
+		t.Fatalf("buildFromFlags: %v", err)
+	}
+	if got.TerraformVersion != ">= 1.0" {
+		t.Errorf("TerraformVersion = %q, want %q (from config)", got.TerraformVersion, ">= 1.0")
+	}
+	if !reflect.DeepEqual(got.Providers, config.Providers) {
+		t.Errorf("Providers = %#v, want %#v (from config)", got.Providers, config.Providers)
+	}
+
+	got, err = buildFromFlags(flagSet{
+		name:      "vpc",
+		tfVersion: ">= 1.5",
+		providers: providerFlags{"aws=hashicorp/aws@5.0.0"},
+	}, config)
+	if err != nil {
+		t.Fatalf("buildFromFlags: %v", err)
+	}
+	if got.TerraformVersion != ">= 1.5" {
+		t.Errorf("TerraformVersion = %q, want %q (from flag)", got.TerraformVersion, ">= 1.5")
+	}
+	wantProviders := []Provider{{Name: "aws", Source: "hashicorp/aws", Version: "5.0.0"}}
+	if !reflect.DeepEqual(got.Providers, wantProviders) {
+		t.Errorf("Providers = %#v, want %#v (from flag)", got.Providers, wantProviders)
+	}
+}
+
+func TestBuildFromFlagsInvalidProvider(t *testing.T) {
+	_, err := buildFromFlags(flagSet{name: "vpc", providers: providerFlags{"not-a-provider-flag"}}, Config{})
+	if err == nil {
+		t.Fatal("buildFromFlags with invalid -provider = nil error, want error")
+	}
+}
+
+func TestModuleDataFromManifest(t *testing.T) {
+	manifest := `{
+		"module_name": "vpc",
+		"description": "a vpc",
+		"terraform_version": ">= 1.0",
+		"providers": [{"name": "aws", "source": "hashicorp/aws", "version": "5.0.0"}],
+		"variables": [{"name": "cidr_block", "description": "CIDR", "type": "string", "default": "10.0.0.0/16"}],
+		"outputs": [{"name": "vpc_id", "description": "VPC ID", "value": "aws_vpc.this.id"}],
+		"examples": ["basic", "complete"]
+	}`
+
+	got, err := moduleDataFromManifest(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("moduleDataFromManifest: %v", err)
+	}
+
+	want := ModuleData{
+		ModuleName:       "vpc",
+		Description:      "a vpc",
+		TerraformVersion: ">= 1.0",
+		Providers:        []Provider{{Name: "aws", Source: "hashicorp/aws", Version: "5.0.0"}},
+		Variables:        []VariableSpec{{Name: "cidr_block", Description: "CIDR", Type: "string", Default: `"10.0.0.0/16"`}},
+		Outputs:          []OutputSpec{{Name: "vpc_id", Description: "VPC ID", Value: "aws_vpc.this.id"}},
+		Examples:         []string{"basic", "complete"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("moduleDataFromManifest() = %#v, want %#v", got, want)
+	}
+}
+
+func TestModuleDataFromManifestMissingName(t *testing.T) {
+	_, err := moduleDataFromManifest(strings.NewReader(`{"description": "no name"}`))
+	if err == nil {
+		t.Fatal("moduleDataFromManifest with no module_name = nil error, want error")
+	}
+}
+
+func TestModuleDataFromManifestAlreadyQuotedDefault(t *testing.T) {
+	manifest := `{"module_name": "vpc", "variables": [{"name": "cidr_block", "type": "string", "default": "\"10.0.0.0/16\""}]}`
+
+	got, err := moduleDataFromManifest(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("moduleDataFromManifest: %v", err)
+	}
+
+	want := `"10.0.0.0/16"`
+	if got.Variables[0].Default != want {
+		t.Fatalf("Variables[0].Default = %q, want %q", got.Variables[0].Default, want)
+	}
+}
+
+func TestModuleDataFromManifestNonStringDefaultUntouched(t *testing.T) {
+	manifest := `{"module_name": "vpc", "variables": [{"name": "replicas", "type": "number", "default": "3"}]}`
+
+	got, err := moduleDataFromManifest(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("moduleDataFromManifest: %v", err)
+	}
+
+	if got.Variables[0].Default != "3" {
+		t.Fatalf("Variables[0].Default = %q, want %q", got.Variables[0].Default, "3")
+	}
+}
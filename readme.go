@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// VariableDoc describes a single input variable for the README Inputs table.
+type VariableDoc struct {
+	Name        string
+	Description string
+	Type        string
+	Default     string
+	Required    bool
+}
+
+// OutputDoc describes a single output for the README Outputs table.
+type OutputDoc struct {
+	Name        string
+	Description string
+}
+
+// ReadmeData is the data used to render a module README, whether the module
+// is brand new (interactive flow) or already exists on disk (docs subcommand).
+type ReadmeData struct {
+	ModuleName       string
+	Description      string
+	TerraformVersion string
+	Providers        []Provider
+	Variables        []VariableDoc
+	Outputs          []OutputDoc
+	// Custom holds hand-written content found between the
+	// BEGIN_CUSTOM/END_CUSTOM markers of an existing README, preserved as-is.
+	Custom string
+}
+
+const (
+	beginCustomMarker = "<!-- BEGIN_CUSTOM -->"
+	endCustomMarker   = "<!-- END_CUSTOM -->"
+)
+
+// renderReadme renders a module README from data, used by both the
+// interactive generator and the docs subcommand so the two flows stay in
+// sync.
+func renderReadme(data ReadmeData, resolver TemplateResolver) (string, error) {
+	tmplStr, err := resolver.Get(templateReadme)
+	if err != nil {
+		return "", fmt.Errorf("resolving readme template: %w", err)
+	}
+
+	tmpl, err := template.New(templateReadme).Funcs(template.FuncMap{
+		"codeFence": func() string { return "```" },
+	}).Parse(tmplStr)
+	if err != nil {
+		return "", fmt.Errorf("parsing readme template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("executing readme template: %w", err)
+	}
+	return buf.String(), nil
+}
+
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+)
+
+// defaultRegistryURL is the public Terraform Registry.
+const defaultRegistryURL = "https://registry.terraform.io"
+
+// Registry looks up a provider's source and latest version from a short
+// name, e.g. "google" -> hashicorp/google @ 6.4.0.
+type Registry interface {
+	Lookup(name string) (Provider, error)
+}
+
+// httpRegistry is a Registry backed by the Terraform Registry HTTP API,
+// with an in-memory cache so repeated lookups of the same provider during
+// one run don't hit the network twice.
+type httpRegistry struct {
+	baseURL string
+	client  *http.Client
+	reader  *bufio.Reader
+
+	mu    sync.Mutex
+	cache map[string]Provider
+}
+
+// newHTTPRegistry returns a Registry querying baseURL (e.g.
+// "https://registry.terraform.io"). reader is used to prompt the user when
+// a provider name is published by more than one namespace.
+func newHTTPRegistry(baseURL string, reader *bufio.Reader) *httpRegistry {
+	return &httpRegistry{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{},
+		reader:  reader,
+		cache:   make(map[string]Provider),
+	}
+}
+
+// providerSearchResponse is the shape of GET /v1/providers/-/<name>.
+type providerSearchResponse struct {
+	Providers []struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"providers"`
+}
+
+// providerVersionsResponse is the shape of
+// GET /v1/providers/<namespace>/<name>/versions.
+type providerVersionsResponse struct {
+	Versions []struct {
+		Version string `json:"version"`
+	} `json:"versions"`
+}
+
+func (r *httpRegistry) Lookup(name string) (Provider, error) {
+	r.mu.Lock()
+	if cached, ok := r.cache[name]; ok {
+		r.mu.Unlock()
+		return cached, nil
+	}
+	r.mu.Unlock()
+
+	namespace, err := r.resolveNamespace(name)
+	if err != nil {
+		return Provider{}, err
+	}
+
+	version, err := r.latestVersion(namespace, name)
+	if err != nil {
+		return Provider{}, err
+	}
+
+	provider := Provider{
+		Name:    name,
+		Source:  namespace + "/" + name,
+		Version: version,
+	}
+
+	r.mu.Lock()
+	r.cache[name] = provider
+	r.mu.Unlock()
+
+	return provider, nil
+}
+
+// resolveNamespace finds which namespace(s) publish a provider with the
+// given short name, preferring "hashicorp" and otherwise prompting the user
+// to disambiguate.
+func (r *httpRegistry) resolveNamespace(name string) (string, error) {
+	var result providerSearchResponse
+	if err := r.get(fmt.Sprintf("/v1/providers/-/%s", name), &result); err != nil {
+		return "", fmt.Errorf("looking up provider %q: %w", name, err)
+	}
+
+	var namespaces []string
+	for _, p := range result.Providers {
+		if p.Name == name {
+			namespaces = append(namespaces, p.Namespace)
+		}
+	}
+	if len(namespaces) == 0 {
+		return "", fmt.Errorf("no provider named %q found in registry", name)
+	}
+
+	for _, ns := range namespaces {
+		if ns == "hashicorp" {
+			return ns, nil
+		}
+	}
+
+	if len(namespaces) == 1 {
+		return namespaces[0], nil
+	}
+
+	sort.Strings(namespaces)
+	fmt.Printf("Multiple namespaces publish a provider named %q:\n", name)
+	for i, ns := range namespaces {
+		fmt.Printf("  %d) %s\n", i+1, ns)
+	}
+	fmt.Print("Choose a namespace (number): ")
+	choice, err := r.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("reading namespace choice: %w", err)
+	}
+	choice = strings.TrimSpace(choice)
+
+	for i, ns := range namespaces {
+		if fmt.Sprintf("%d", i+1) == choice {
+			return ns, nil
+		}
+	}
+	return "", fmt.Errorf("invalid namespace choice %q", choice)
+}
+
+// latestVersion returns the newest published version for namespace/name,
+// determined by semver comparison rather than the order the registry API
+// happens to return them in.
+func (r *httpRegistry) latestVersion(namespace, name string) (string, error) {
+	var result providerVersionsResponse
+	if err := r.get(fmt.Sprintf("/v1/providers/%s/%s/versions", namespace, name), &result); err != nil {
+		return "", fmt.Errorf("listing versions for %s/%s: %w", namespace, name, err)
+	}
+	if len(result.Versions) == 0 {
+		return "", fmt.Errorf("no versions published for %s/%s", namespace, name)
+	}
+
+	var latest *version.Version
+	latestRaw := result.Versions[0].Version
+	for _, v := range result.Versions {
+		parsed, err := version.NewVersion(v.Version)
+		if err != nil {
+			continue
+		}
+		if latest == nil || parsed.GreaterThan(latest) {
+			latest = parsed
+			latestRaw = v.Version
+		}
+	}
+	return latestRaw, nil
+}
+
+func (r *httpRegistry) get(path string, out interface{}) error {
+	resp, err := r.client.Get(r.baseURL + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry returned %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPRegistryLookupSingleNamespace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/providers/-/google":
+			fmt.Fprint(w, `{"providers":[{"namespace":"hashicorp","name":"google"}]}`)
+		case "/v1/providers/hashicorp/google/versions":
+			fmt.Fprint(w, `{"versions":[{"version":"6.4.0"}]}`)
+		default:
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	registry := newHTTPRegistry(server.URL, bufio.NewReader(strings.NewReader("")))
+
+	got, err := registry.Lookup("google")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	want := Provider{Name: "google", Source: "hashicorp/google", Version: "6.4.0"}
+	if got != want {
+		t.Fatalf("Lookup() = %#v, want %#v", got, want)
+	}
+}
+
+func TestHTTPRegistryLookupPrefersHashicorpNamespace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/providers/-/vault":
+			fmt.Fprint(w, `{"providers":[{"namespace":"someoneelse","name":"vault"},{"namespace":"hashicorp","name":"vault"}]}`)
+		case "/v1/providers/hashicorp/vault/versions":
+			fmt.Fprint(w, `{"versions":[{"version":"4.0.0"}]}`)
+		default:
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	registry := newHTTPRegistry(server.URL, bufio.NewReader(strings.NewReader("")))
+
+	got, err := registry.Lookup("vault")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if got.Source != "hashicorp/vault" {
+		t.Fatalf("Lookup().Source = %q, want %q", got.Source, "hashicorp/vault")
+	}
+}
+
+func TestHTTPRegistryLookupPromptsForAmbiguousNamespace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/providers/-/widget":
+			fmt.Fprint(w, `{"providers":[{"namespace":"bravo","name":"widget"},{"namespace":"alpha","name":"widget"}]}`)
+		case "/v1/providers/alpha/widget/versions":
+			fmt.Fprint(w, `{"versions":[{"version":"1.0.0"}]}`)
+		default:
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	// Namespaces are sorted before prompting ("alpha", "bravo"), so "1"
+	// picks "alpha".
+	registry := newHTTPRegistry(server.URL, bufio.NewReader(strings.NewReader("1\n")))
+
+	got, err := registry.Lookup("widget")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if got.Source != "alpha/widget" {
+		t.Fatalf("Lookup().Source = %q, want %q", got.Source, "alpha/widget")
+	}
+}
+
+func TestHTTPRegistryLookupNoProviderFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"providers":[]}`)
+	}))
+	defer server.Close()
+
+	registry := newHTTPRegistry(server.URL, bufio.NewReader(strings.NewReader("")))
+
+	if _, err := registry.Lookup("nonexistent"); err == nil {
+		t.Fatal("Lookup() = nil error, want error")
+	}
+}
+
+func TestHTTPRegistryLookupCachesResult(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/v1/providers/-/aws":
+			fmt.Fprint(w, `{"providers":[{"namespace":"hashicorp","name":"aws"}]}`)
+		case "/v1/providers/hashicorp/aws/versions":
+			fmt.Fprint(w, `{"versions":[{"version":"5.0.0"}]}`)
+		default:
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	registry := newHTTPRegistry(server.URL, bufio.NewReader(strings.NewReader("")))
+
+	if _, err := registry.Lookup("aws"); err != nil {
+		t.Fatalf("first Lookup: %v", err)
+	}
+	if _, err := registry.Lookup("aws"); err != nil {
+		t.Fatalf("second Lookup: %v", err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("server received %d requests, want 2 (namespace + versions, once)", requests)
+	}
+}
+
+func TestHTTPRegistryLatestVersionIgnoresAPIOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/providers/-/aws":
+			fmt.Fprint(w, `{"providers":[{"namespace":"hashicorp","name":"aws"}]}`)
+		case "/v1/providers/hashicorp/aws/versions":
+			// Deliberately out of semver order, and not in lexical order
+			// either, to prove the comparison is semver-aware.
+			fmt.Fprint(w, `{"versions":[{"version":"3.9.0"},{"version":"3.100.0"},{"version":"3.2.0"}]}`)
+		default:
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	registry := newHTTPRegistry(server.URL, bufio.NewReader(strings.NewReader("")))
+
+	got, err := registry.Lookup("aws")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if got.Version != "3.100.0" {
+		t.Fatalf("Lookup().Version = %q, want %q", got.Version, "3.100.0")
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Template names, shared between the embedded defaults and any
+// user-supplied -templates-dir override.
+const (
+	templateVersions = "versions.tf.tmpl"
+	templateMain     = "main.tf.tmpl"
+	templateOutput   = "output.tf.tmpl"
+	templateVariable = "variable.tf.tmpl"
+	templateReadme   = "README.md.tmpl"
+
+	templateExampleMain      = "examples/main.tf.tmpl"
+	templateExampleVariables = "examples/variables.tf.tmpl"
+	templateExampleOutputs   = "examples/outputs.tf.tmpl"
+	templateExampleTfvars    = "examples/terraform.tfvars.example.tmpl"
+	templateExampleReadme    = "examples/README.md.tmpl"
+)
+
+//go:embed templates/versions.tf.tmpl templates/main.tf.tmpl templates/output.tf.tmpl templates/variable.tf.tmpl templates/README.md.tmpl
+//go:embed templates/examples/main.tf.tmpl templates/examples/variables.tf.tmpl templates/examples/outputs.tf.tmpl templates/examples/terraform.tfvars.example.tmpl templates/examples/README.md.tmpl
+var embeddedTemplates embed.FS
+
+// TemplateResolver resolves a template by name to its contents. This lets
+// callers swap in user-supplied overrides without changing how templates
+// are looked up.
+type TemplateResolver interface {
+	Get(name string) (string, error)
+}
+
+// embeddedResolver serves the built-in default templates.
+type embeddedResolver struct{}
+
+func (embeddedResolver) Get(name string) (string, error) {
+	content, err := embeddedTemplates.ReadFile(path.Join("templates", name))
+	if err != nil {
+		return "", fmt.Errorf("embedded template %q: %w", name, err)
+	}
+	return string(content), nil
+}
+
+// filesystemResolver serves templates from a user-supplied directory,
+// falling back to another resolver (normally embeddedResolver) for any
+// template file that isn't present there.
+type filesystemResolver struct {
+	dir      string
+	fallback TemplateResolver
+}
+
+// newFilesystemResolver returns a TemplateResolver that prefers files in
+// dir, by filename, and falls back to fallback otherwise.
+func newFilesystemResolver(dir string, fallback TemplateResolver) TemplateResolver {
+	return filesystemResolver{dir: dir, fallback: fallback}
+}
+
+func (r filesystemResolver) Get(name string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(r.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r.fallback.Get(name)
+		}
+		return "", fmt.Errorf("reading template override %q: %w", name, err)
+	}
+	return string(content), nil
+}
+
+// defaultResolver returns the TemplateResolver to use given an optional
+// -templates-dir override: the embedded defaults, or a filesystemResolver
+// layered on top of them when a directory is given.
+func defaultResolver(templatesDir string) TemplateResolver {
+	var resolver TemplateResolver = embeddedResolver{}
+	if templatesDir != "" {
+		resolver = newFilesystemResolver(templatesDir, resolver)
+	}
+	return resolver
+}
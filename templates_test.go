@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilesystemResolverPrefersOverride(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, templateMain, "overridden main.tf\n")
+
+	resolver := newFilesystemResolver(dir, embeddedResolver{})
+
+	got, err := resolver.Get(templateMain)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got != "overridden main.tf\n" {
+		t.Fatalf("Get(%q) = %q, want the override content", templateMain, got)
+	}
+}
+
+func TestFilesystemResolverFallsBackToDefault(t *testing.T) {
+	dir := t.TempDir()
+
+	resolver := newFilesystemResolver(dir, embeddedResolver{})
+
+	got, err := resolver.Get(templateMain)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	want, err := embeddedResolver{}.Get(templateMain)
+	if err != nil {
+		t.Fatalf("embeddedResolver.Get: %v", err)
+	}
+	if got != want {
+		t.Fatalf("Get(%q) = %q, want the embedded default %q", templateMain, got, want)
+	}
+}
+
+func TestFilesystemResolverPropagatesOtherErrors(t *testing.T) {
+	// A directory where the override file itself isn't readable as a file
+	// (it's a directory), which should surface as an error rather than
+	// falling back, since it isn't the "file doesn't exist" case.
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, templateMain), 0755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+
+	resolver := newFilesystemResolver(dir, embeddedResolver{})
+	if _, err := resolver.Get(templateMain); err == nil {
+		t.Fatal("Get() = nil error, want error reading the override")
+	}
+}
+
+func TestDefaultResolverNoOverride(t *testing.T) {
+	resolver := defaultResolver("")
+	if _, ok := resolver.(embeddedResolver); !ok {
+		t.Fatalf("defaultResolver(\"\") = %T, want embeddedResolver", resolver)
+	}
+}
+
+func TestDefaultResolverWithOverride(t *testing.T) {
+	dir := t.TempDir()
+	resolver := defaultResolver(dir)
+	if _, ok := resolver.(filesystemResolver); !ok {
+		t.Fatalf("defaultResolver(%q) = %T, want filesystemResolver", dir, resolver)
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// wellKnownProviderSources maps short provider names to their Terraform
+// Registry source for the providers HashiCorp publishes directly. This is
+// checked before falling back to a Registry lookup.
+var wellKnownProviderSources = map[string]string{
+	"aws":        "hashicorp/aws",
+	"google":     "hashicorp/google",
+	"azurerm":    "hashicorp/azurerm",
+	"kubernetes": "hashicorp/kubernetes",
+	"helm":       "hashicorp/helm",
+	"random":     "hashicorp/random",
+	"null":       "hashicorp/null",
+	"local":      "hashicorp/local",
+	"tls":        "hashicorp/tls",
+	"time":       "hashicorp/time",
+	"archive":    "hashicorp/archive",
+	"external":   "hashicorp/external",
+	"http":       "hashicorp/http",
+	"cloudinit":  "hashicorp/cloudinit",
+	"template":   "hashicorp/template",
+}
+
+// legacyDefaultVersion is used as the version constraint for a provider
+// that's missing a required_providers entry entirely, where no constraint
+// can be recovered from the existing configuration.
+const legacyDefaultVersion = ">= 0.0.0"
+
+// runUpgrade implements `tfmodulegen upgrade <module-dir>`: it finds legacy
+// provider declarations in the module and rewrites versions.tf to the
+// modern required_providers block form.
+func runUpgrade(args []string) error {
+	fs := flag.NewFlagSet("upgrade", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "print a unified diff instead of writing changes")
+	yes := fs.Bool("yes", false, "skip the confirmation prompt")
+	offline := fs.Bool("offline", false, "disable Terraform Registry provider lookups")
+	registryURL := fs.String("registry-url", defaultRegistryURL, "Terraform Registry base URL used for provider lookups")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		return fmt.Errorf("usage: tfmodulegen upgrade <module-dir>")
+	}
+	dir := fs.Arg(0)
+
+	reader := bufio.NewReader(os.Stdin)
+	var registry Registry
+	if !*offline {
+		registry = newHTTPRegistry(*registryURL, reader)
+	}
+
+	versionsPath := filepath.Join(dir, "versions.tf")
+	original, err := os.ReadFile(versionsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading versions.tf: %w", err)
+	}
+	if original == nil {
+		original = []byte("terraform {\n}\n")
+	}
+
+	bareProviders, err := findBareProviders(dir)
+	if err != nil {
+		return fmt.Errorf("scanning module for provider blocks: %w", err)
+	}
+
+	f, diags := hclwrite.ParseConfig(original, versionsPath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return fmt.Errorf("parsing versions.tf: %w", diags)
+	}
+
+	tfBlock := findOrCreateBlock(f.Body(), "terraform", nil)
+	reqBlock := findOrCreateBlock(tfBlock.Body(), "required_providers", nil)
+
+	declared := map[string]bool{}
+	shorthand := map[string]string{}
+	for name, attr := range reqBlock.Body().Attributes() {
+		raw := strings.TrimSpace(string(attr.Expr().BuildTokens(nil).Bytes()))
+		declared[name] = true
+		if strings.HasPrefix(raw, `"`) {
+			shorthand[name] = unquote(raw)
+		}
+	}
+
+	toRewrite := map[string]string{} // name -> version constraint
+	for name, constraint := range shorthand {
+		toRewrite[name] = constraint
+	}
+	for _, name := range bareProviders {
+		if declared[name] && !isShorthand(shorthand, name) {
+			continue
+		}
+		if _, already := toRewrite[name]; !already {
+			toRewrite[name] = legacyDefaultVersion
+		}
+	}
+
+	if len(toRewrite) == 0 {
+		fmt.Println("No legacy provider declarations found; nothing to upgrade.")
+		return nil
+	}
+
+	names := make([]string, 0, len(toRewrite))
+	for name := range toRewrite {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		version := toRewrite[name]
+		source, ok := resolveProviderSource(name, registry)
+		if ok {
+			reqBlock.Body().SetAttributeValue(name, cty.ObjectVal(map[string]cty.Value{
+				"source":  cty.StringVal(source),
+				"version": cty.StringVal(version),
+			}))
+			continue
+		}
+		setUnresolvedSource(reqBlock.Body(), name, version)
+	}
+
+	updated := f.Bytes()
+
+	if *dryRun {
+		diff, err := unifiedDiff(original, updated, versionsPath)
+		if err != nil {
+			return err
+		}
+		fmt.Print(diff)
+		return nil
+	}
+
+	if !*yes {
+		fmt.Printf("Rewrite %s with modern required_providers blocks for: %s\nProceed? (y/n): ", versionsPath, strings.Join(names, ", "))
+		ans, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("reading confirmation: %w", err)
+		}
+		ans = strings.ToLower(strings.TrimSpace(ans))
+		if ans != "y" && ans != "yes" {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	if err := os.WriteFile(versionsPath, updated, 0644); err != nil {
+		return fmt.Errorf("writing versions.tf: %w", err)
+	}
+	fmt.Printf("Rewrote %s\n", versionsPath)
+	return nil
+}
+
+// findBareProviders scans every .tf file in dir for legacy
+// `provider "name" {}` blocks and returns their names.
+func findBareProviders(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tf") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		f, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("parsing %s: %w", path, diags)
+		}
+		for _, block := range f.Body().Blocks() {
+			if block.Type() != "provider" || len(block.Labels()) != 1 {
+				continue
+			}
+			name := block.Labels()[0]
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// findOrCreateBlock returns the first block of the given type in body,
+// creating one with the given labels if none exists.
+func findOrCreateBlock(body *hclwrite.Body, blockType string, labels []string) *hclwrite.Block {
+	for _, block := range body.Blocks() {
+		if block.Type() == blockType {
+			return block
+		}
+	}
+	return body.AppendNewBlock(blockType, labels)
+}
+
+// isShorthand reports whether name was already counted in shorthand, so a
+// bare provider block matching a shorthand entry isn't double-counted with
+// the legacy default version.
+func isShorthand(shorthand map[string]string, name string) bool {
+	_, ok := shorthand[name]
+	return ok
+}
+
+// resolveProviderSource finds the registry source for a short provider
+// name, checking the built-in map first and falling back to registry (when
+// available).
+func resolveProviderSource(name string, registry Registry) (string, bool) {
+	if source, ok := wellKnownProviderSources[name]; ok {
+		return source, true
+	}
+	if registry == nil {
+		return "", false
+	}
+	provider, err := registry.Lookup(name)
+	if err != nil || provider.Source == "" {
+		return "", false
+	}
+	return provider.Source, true
+}
+
+// setUnresolvedSource writes a required_providers entry for a provider
+// whose source could not be determined, leaving a TODO comment instead of
+// silently dropping it. The object value is built through
+// SetAttributeValue so hclwrite's own formatter lays it out and aligns it
+// like every other entry; a TODO comment token is then spliced in right
+// after the opening brace.
+func setUnresolvedSource(body *hclwrite.Body, name, version string) {
+	body.SetAttributeValue(name, cty.ObjectVal(map[string]cty.Value{
+		"version": cty.StringVal(version),
+	}))
+
+	attr := body.GetAttribute(name)
+	tokens := attr.Expr().BuildTokens(nil)
+
+	withComment := make(hclwrite.Tokens, 0, len(tokens)+2)
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		withComment = append(withComment, tok)
+		if tok.Type == hclsyntax.TokenOBrace && i+1 < len(tokens) && tokens[i+1].Type == hclsyntax.TokenNewline {
+			i++
+			withComment = append(withComment, tokens[i], &hclwrite.Token{
+				Type:  hclsyntax.TokenComment,
+				Bytes: []byte("# TODO: set source\n"),
+			})
+		}
+	}
+
+	body.SetAttributeRaw(name, withComment)
+}
+
+// unifiedDiff shells out to `diff -u` to render a unified diff between the
+// original and updated content of path.
+func unifiedDiff(original, updated []byte, path string) (string, error) {
+	oldFile, err := os.CreateTemp("", "tfmodulegen-upgrade-old-*.tf")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(oldFile.Name())
+	defer oldFile.Close()
+
+	newFile, err := os.CreateTemp("", "tfmodulegen-upgrade-new-*.tf")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(newFile.Name())
+	defer newFile.Close()
+
+	if _, err := oldFile.Write(original); err != nil {
+		return "", err
+	}
+	if _, err := newFile.Write(updated); err != nil {
+		return "", err
+	}
+
+	out, err := exec.Command("diff", "-u", "--label", path+".orig", "--label", path, oldFile.Name(), newFile.Name()).CombinedOutput()
+	// diff exits 1 when the files differ; that's not an error for us.
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return string(out), nil
+		}
+		return "", fmt.Errorf("running diff: %w", err)
+	}
+	return string(out), nil
+}
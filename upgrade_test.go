@@ -0,0 +1,120 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFindBareProviders(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "main.tf", `
+provider "aws" {}
+
+resource "aws_instance" "this" {}
+`)
+	writeTestFile(t, dir, "other.tf", `
+provider "google" {}
+provider "aws" {}
+`)
+
+	got, err := findBareProviders(dir)
+	if err != nil {
+		t.Fatalf("findBareProviders: %v", err)
+	}
+	sort.Strings(got)
+
+	want := []string{"aws", "google"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("findBareProviders() = %#v, want %#v", got, want)
+	}
+}
+
+func TestFindBareProvidersNoProviders(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "main.tf", `resource "null_resource" "this" {}`)
+
+	got, err := findBareProviders(dir)
+	if err != nil {
+		t.Fatalf("findBareProviders: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("findBareProviders() = %#v, want nil", got)
+	}
+}
+
+func TestResolveProviderSource(t *testing.T) {
+	source, ok := resolveProviderSource("aws", nil)
+	if !ok || source != "hashicorp/aws" {
+		t.Fatalf("resolveProviderSource(aws) = (%q, %v), want (hashicorp/aws, true)", source, ok)
+	}
+
+	source, ok = resolveProviderSource("totally-unknown-provider", nil)
+	if ok || source != "" {
+		t.Fatalf("resolveProviderSource(unknown) = (%q, %v), want (\"\", false)", source, ok)
+	}
+}
+
+func TestRunUpgradeRewritesLegacyProviders(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "main.tf", `
+provider "random" {}
+`)
+	writeTestFile(t, dir, "versions.tf", `
+terraform {
+  required_providers {
+    aws = "~> 3.0"
+  }
+}
+`)
+
+	if err := runUpgrade([]string{"-offline", "-yes", dir}); err != nil {
+		t.Fatalf("runUpgrade: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "versions.tf"))
+	if err != nil {
+		t.Fatalf("reading versions.tf: %v", err)
+	}
+	got := string(content)
+
+	for _, want := range []string{
+		`source  = "hashicorp/aws"`,
+		`version = "~> 3.0"`,
+		`source  = "hashicorp/random"`,
+		`version = "` + legacyDefaultVersion + `"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("versions.tf missing %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunUpgradeNoLegacyProviders(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "versions.tf", `
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "5.0.0"
+    }
+  }
+}
+`)
+
+	if err := runUpgrade([]string{"-offline", "-yes", dir}); err != nil {
+		t.Fatalf("runUpgrade: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "versions.tf"))
+	if err != nil {
+		t.Fatalf("reading versions.tf: %v", err)
+	}
+	if strings.Count(string(content), "hashicorp/aws") != 1 {
+		t.Fatalf("versions.tf was modified when no legacy providers were present:\n%s", content)
+	}
+}